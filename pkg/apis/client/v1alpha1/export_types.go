@@ -0,0 +1,52 @@
+// Copyright © 2020 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package v1alpha1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// Export is the client-side bundle produced by 'kn service export': a
+// normalized Service plus, optionally, the historical revisions and
+// referenced ConfigMaps/Secrets needed to recreate it on another cluster.
+//
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type Export struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Spec holds the exported service, its revisions and its dependencies.
+	Spec ExportSpec `json:"spec"`
+}
+
+// ExportSpec is the spec of an Export.
+type ExportSpec struct {
+	// Service is the latest generation of the exported service.
+	Service servingv1.Service `json:"service"`
+
+	// Revisions holds the historical, routed revisions of the exported
+	// service, in generation order. Populated only with '--with-revisions'.
+	Revisions []servingv1.Revision `json:"revisions,omitempty"`
+
+	// ConfigMaps holds the ConfigMaps referenced by the exported service's
+	// revision template. Populated only with '--include-referenced'.
+	ConfigMaps []corev1.ConfigMap `json:"configMaps,omitempty"`
+
+	// Secrets holds the Secrets referenced by the exported service's
+	// revision template. Populated only with '--include-referenced'.
+	Secrets []corev1.Secret `json:"secrets,omitempty"`
+}
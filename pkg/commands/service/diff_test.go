@@ -0,0 +1,81 @@
+// Copyright © 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"bytes"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// TestDiffAndPrintIgnoresRouteTraffic is a regression test: a plain 'kn
+// service export' (no '--with-revisions') never populates Spec.RouteSpec,
+// while the live Service almost always has spec.traffic defaulted by the
+// webhook. normalizeForDiff must not report a diff purely because of that
+// asymmetry.
+func TestDiffAndPrintIgnoresRouteTraffic(t *testing.T) {
+	local := &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		// no RouteSpec, as a plain export would produce
+	}
+	live := &servingv1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "foo"},
+		Spec: servingv1.ServiceSpec{
+			ConfigurationSpec: local.Spec.ConfigurationSpec,
+			RouteSpec: servingv1.RouteSpec{
+				Traffic: []servingv1.TrafficTarget{{
+					RevisionName: "foo-00001",
+					Percent:      ptrInt64(100),
+				}},
+			},
+		},
+	}
+
+	changed, err := diffAndPrint(&bytes.Buffer{}, "service", "foo", local, live, false)
+	if err != nil {
+		t.Fatalf("diffAndPrint() returned error: %v", err)
+	}
+	if changed {
+		t.Errorf("diffAndPrint() reported a diff for route traffic alone, want no diff")
+	}
+}
+
+// TestDiffAndPrintReportsTemplateChange makes sure a real difference in the
+// revision template is still caught once routing is excluded from the
+// comparison.
+func TestDiffAndPrintReportsTemplateChange(t *testing.T) {
+	local := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+	live := &servingv1.Service{ObjectMeta: metav1.ObjectMeta{Name: "foo"}}
+	live.Spec.Template.ObjectMeta.Name = "foo-00002"
+
+	var buf bytes.Buffer
+	changed, err := diffAndPrint(&buf, "service", "foo", local, live, false)
+	if err != nil {
+		t.Fatalf("diffAndPrint() returned error: %v", err)
+	}
+	if !changed {
+		t.Errorf("diffAndPrint() reported no diff for a changed revision template name")
+	}
+	if buf.Len() == 0 {
+		t.Errorf("diffAndPrint() did not write a diff for a real change")
+	}
+}
+
+func ptrInt64(v int64) *int64 {
+	return &v
+}
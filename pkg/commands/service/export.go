@@ -28,6 +28,7 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/cli-runtime/pkg/genericclioptions"
 	"k8s.io/cli-runtime/pkg/printers"
 
@@ -94,46 +95,178 @@ func NewServiceExportCommand(p *commands.KnParams) *cobra.Command {
   kn service export foo --with-revisions --mode=export -n bar -o json
 
   # Export services in kubectl friendly format, as a list kind, one service item for each revision (Beta)
-  kn service export foo --with-revisions --mode=replay -n bar -o json`,
+  kn service export foo --with-revisions --mode=replay -n bar -o json
+
+  # Export every service matching a label selector in the current namespace (Beta)
+  kn service export -l app=foo -n bar -o yaml
+
+  # Export every service in every namespace (Beta)
+  kn service export --all-namespaces -o yaml
+
+  # Snapshot a service across dev, stage and prod in one invocation (Beta)
+  kn service export foo -n bar --contexts dev,stage,prod -o yaml
+
+  # Snapshot a service across every context in the kubeconfig (Beta)
+  kn service export foo -n bar --all-contexts -o yaml`,
 		RunE: func(cmd *cobra.Command, args []string) error {
-			if len(args) != 1 {
-				return errors.New("'kn service export' requires name of the service as single argument")
-			}
-			if !machineReadablePrintFlags.OutputFlagSpecified() {
-				return errors.New("'kn service export' requires output format")
+			selector, err := cmd.Flags().GetString("selector")
+			if err != nil {
+				return err
 			}
-			serviceName := args[0]
-
-			namespace, err := p.GetNamespace(cmd)
+			allNamespaces, err := cmd.Flags().GetBool("all-namespaces")
 			if err != nil {
 				return err
 			}
-
-			client, err := p.NewServingClient(namespace)
+			contextsFlag, err := cmd.Flags().GetString("contexts")
 			if err != nil {
 				return err
 			}
-
-			service, err := client.GetService(cmd.Context(), serviceName)
+			allContexts, err := cmd.Flags().GetBool("all-contexts")
 			if err != nil {
 				return err
 			}
+			if contextsFlag != "" && allContexts {
+				return errors.New("only one of '--contexts' or '--all-contexts' may be specified")
+			}
+			if !machineReadablePrintFlags.OutputFlagSpecified() {
+				return errors.New("'kn service export' requires output format")
+			}
 			printer, err := machineReadablePrintFlags.ToPrinter()
 			if err != nil {
 				return err
 			}
-			return exportService(cmd, service, client, printer)
+
+			if contextsFlag != "" || allContexts {
+				if len(args) != 1 || selector != "" || allNamespaces {
+					return errors.New("'--contexts'/'--all-contexts' require the name of a single service as argument")
+				}
+				contexts, err := resolveContexts(p, contextsFlag, allContexts)
+				if err != nil {
+					return err
+				}
+				return exportServiceAcrossContexts(cmd, p, args[0], contexts, printer)
+			}
+
+			if len(args) == 1 && selector == "" && !allNamespaces {
+				return exportSingleService(cmd, p, args[0], printer)
+			}
+			if len(args) != 0 {
+				return errors.New("'kn service export' requires either the name of the service as single argument, or '--selector'/'--all-namespaces'")
+			}
+			if selector == "" && !allNamespaces {
+				return errors.New("'kn service export' requires name of the service as single argument")
+			}
+			return exportServices(cmd, p, selector, allNamespaces, printer)
 		},
 	}
 	flags := command.Flags()
 	commands.AddNamespaceFlags(flags, false)
 	flags.Bool("with-revisions", false, "Export all routed revisions (Beta)")
 	flags.String("mode", "", "Format for exporting all routed revisions. One of replay|export (Beta)")
+	flags.StringP("selector", "l", "", "Export services matching a label selector, same syntax as 'kubectl get -l' (Beta)")
+	flags.BoolP("all-namespaces", "A", false, "Export matching services across all namespaces (Beta)")
+	flags.Bool("include-referenced", false, "Include ConfigMaps and Secrets referenced by the service's env/volumes in the exported bundle (Beta)")
+	flags.Bool("redact-secrets", false, "With '--include-referenced', replace Secret data with a placeholder and record its SHA256 in an annotation (Beta)")
+	flags.String("contexts", "", "Comma separated list of kubeconfig contexts to export the service from, one document per context (Beta)")
+	flags.Bool("all-contexts", false, "Export the service from every context in the kubeconfig (Beta)")
+	flags.Bool("preserve-uids", false, "Keep the original configuration/service UID labels and record the source UIDs in annotations, for cross-cluster pod correlation (Beta)")
 	machineReadablePrintFlags.AddFlags(command)
 	return command
 }
 
-func exportService(cmd *cobra.Command, service *servingv1.Service, client clientservingv1.KnServingClient, printer printers.ResourcePrinter) error {
+// exportSingleService keeps the original, pre-bulk-export behavior of
+// exporting exactly one named service.
+func exportSingleService(cmd *cobra.Command, p *commands.KnParams, serviceName string, printer printers.ResourcePrinter) error {
+	namespace, err := p.GetNamespace(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, err := p.NewServingClient(namespace)
+	if err != nil {
+		return err
+	}
+
+	service, err := client.GetService(cmd.Context(), serviceName)
+	if err != nil {
+		return err
+	}
+	return exportService(cmd, p, namespace, service, client, printer)
+}
+
+// exportServices exports every service matched by selector/allNamespaces as
+// a single List (--mode=replay) or ExportList (default mode).
+func exportServices(cmd *cobra.Command, p *commands.KnParams, selector string, allNamespaces bool, printer printers.ResourcePrinter) error {
+	withRevisions, err := cmd.Flags().GetBool("with-revisions")
+	if err != nil {
+		return err
+	}
+	mode, err := cmd.Flags().GetString("mode")
+	if err != nil {
+		return err
+	}
+	preserveUIDs, err := cmd.Flags().GetBool("preserve-uids")
+	if err != nil {
+		return err
+	}
+
+	namespaces, err := exportNamespaces(cmd, p, allNamespaces)
+	if err != nil {
+		return err
+	}
+
+	sources := make([]exportSource, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		client, err := p.NewServingClient(namespace)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, exportSource{namespace: namespace, client: client})
+	}
+
+	fetchMatchingServices := func(ctx context.Context, source exportSource) ([]*servingv1.Service, error) {
+		svcList, err := source.client.ListServices(ctx, clientservingv1.WithLabelSelector(selector))
+		if err != nil {
+			return nil, err
+		}
+		services := make([]*servingv1.Service, 0, len(svcList.Items))
+		for i := range svcList.Items {
+			services = append(services, svcList.Items[i].DeepCopy())
+		}
+		return services, nil
+	}
+
+	return exportServicesFromSources(cmd, p, sources, mode, withRevisions, preserveUIDs, printer, fetchMatchingServices)
+}
+
+// exportNamespaces resolves the namespace(s) a bulk export should run
+// against: every namespace in the cluster for --all-namespaces, or the
+// namespace selected by the usual namespace flags/config otherwise.
+func exportNamespaces(cmd *cobra.Command, p *commands.KnParams, allNamespaces bool) ([]string, error) {
+	if !allNamespaces {
+		namespace, err := p.GetNamespace(cmd)
+		if err != nil {
+			return nil, err
+		}
+		return []string{namespace}, nil
+	}
+
+	kubeClient, err := p.NewKubeClient()
+	if err != nil {
+		return nil, err
+	}
+	nsList, err := kubeClient.CoreV1().Namespaces().List(cmd.Context(), metav1.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
+	namespaces := make([]string, 0, len(nsList.Items))
+	for _, ns := range nsList.Items {
+		namespaces = append(namespaces, ns.Name)
+	}
+	return namespaces, nil
+}
+
+func exportService(cmd *cobra.Command, p *commands.KnParams, namespace string, service *servingv1.Service, client clientservingv1.KnServingClient, printer printers.ResourcePrinter) error {
 	withRevisions, err := cmd.Flags().GetBool("with-revisions")
 	if err != nil {
 		return err
@@ -144,23 +277,50 @@ func exportService(cmd *cobra.Command, service *servingv1.Service, client client
 		return err
 	}
 
+	preserveUIDs, err := cmd.Flags().GetBool("preserve-uids")
+	if err != nil {
+		return err
+	}
+
+	configMaps, secrets, err := referencedObjectsForService(cmd, p, service, namespace)
+	if err != nil {
+		return err
+	}
+
 	if mode == ModeReplay {
-		svcList, err := exportServiceListForReplay(cmd.Context(), service.DeepCopy(), client, withRevisions)
+		if len(configMaps) == 0 && len(secrets) == 0 {
+			svcList, err := exportServiceListForReplay(cmd.Context(), service.DeepCopy(), client, withRevisions, preserveUIDs)
+			if err != nil {
+				return err
+			}
+			return printer.PrintObj(svcList, cmd.OutOrStdout())
+		}
+		svcItems, err := exportServiceItemsForReplay(cmd.Context(), service.DeepCopy(), client, withRevisions, preserveUIDs)
 		if err != nil {
 			return err
 		}
-		return printer.PrintObj(svcList, cmd.OutOrStdout())
+		return printer.PrintObj(buildReplayList(svcItems, configMaps, secrets), cmd.OutOrStdout())
 	}
 	// default is export mode
-	knExport, err := exportForKNImport(cmd.Context(), service.DeepCopy(), client, withRevisions)
+	knExport, err := exportForKNImport(cmd.Context(), service.DeepCopy(), client, withRevisions, preserveUIDs)
 	if err != nil {
 		return err
 	}
+	knExport.Spec.ConfigMaps = configMaps
+	knExport.Spec.Secrets = secrets
 	//print kn export
 	return printer.PrintObj(knExport, cmd.OutOrStdout())
 }
 
-func exportLatestService(latestSvc *servingv1.Service, withRoutes bool) *servingv1.Service {
+// OriginalServiceUIDAnnotationKey records a Service's original metadata.uid
+// on its exported copy, when exporting with '--preserve-uids'.
+const OriginalServiceUIDAnnotationKey = "client.knative.dev/originalServiceUID"
+
+// OriginalRevisionUIDAnnotationKey records a Revision's original
+// metadata.uid on its exported copy, when exporting with '--preserve-uids'.
+const OriginalRevisionUIDAnnotationKey = "client.knative.dev/originalRevisionUID"
+
+func exportLatestService(latestSvc *servingv1.Service, withRoutes bool, preserveUIDs bool) *servingv1.Service {
 	exportedSvc := servingv1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        latestSvc.ObjectMeta.Name,
@@ -185,13 +345,16 @@ func exportLatestService(latestSvc *servingv1.Service, withRoutes bool) *serving
 	}
 
 	stripIgnoredAnnotationsFromService(&exportedSvc)
-	stripIgnoredLabelsFromService(&exportedSvc)
+	stripIgnoredLabelsFromService(&exportedSvc, preserveUIDs)
 	stripIgnoredAnnotationsFromRevisionTemplate(&exportedSvc.Spec.Template)
-	stripIgnoredLabelsFromRevisionTemplate(&exportedSvc.Spec.Template)
+	stripIgnoredLabelsFromRevisionTemplate(&exportedSvc.Spec.Template, preserveUIDs)
+	if preserveUIDs {
+		recordOriginalUID(&exportedSvc.ObjectMeta, OriginalServiceUIDAnnotationKey, latestSvc.ObjectMeta.UID)
+	}
 	return &exportedSvc
 }
 
-func exportRevision(revision *servingv1.Revision) servingv1.Revision {
+func exportRevision(revision *servingv1.Revision, preserveUIDs bool) servingv1.Revision {
 	exportedRevision := servingv1.Revision{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        revision.ObjectMeta.Name,
@@ -203,11 +366,14 @@ func exportRevision(revision *servingv1.Revision) servingv1.Revision {
 
 	exportedRevision.Spec = revision.Spec
 	stripIgnoredAnnotationsFromRevision(&exportedRevision)
-	stripIgnoredLabelsFromRevision(&exportedRevision)
+	stripIgnoredLabelsFromRevision(&exportedRevision, preserveUIDs)
+	if preserveUIDs {
+		recordOriginalUID(&exportedRevision.ObjectMeta, OriginalRevisionUIDAnnotationKey, revision.ObjectMeta.UID)
+	}
 	return exportedRevision
 }
 
-func constructServiceFromRevision(latestSvc *servingv1.Service, revision *servingv1.Revision) servingv1.Service {
+func constructServiceFromRevision(latestSvc *servingv1.Service, revision *servingv1.Revision, preserveUIDs bool) servingv1.Service {
 	exportedSvc := servingv1.Service{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:        latestSvc.ObjectMeta.Name,
@@ -227,30 +393,34 @@ func constructServiceFromRevision(latestSvc *servingv1.Service, revision *servin
 
 	exportedSvc.Spec.Template.ObjectMeta.Name = revision.ObjectMeta.Name
 	stripIgnoredAnnotationsFromService(&exportedSvc)
+	if preserveUIDs {
+		recordOriginalUID(&exportedSvc.Spec.Template.ObjectMeta, OriginalRevisionUIDAnnotationKey, revision.ObjectMeta.UID)
+	}
 	return exportedSvc
 }
 
-func exportServiceListForReplay(ctx context.Context, latestSvc *servingv1.Service, client clientservingv1.KnServingClient, withRevisions bool) (runtime.Object, error) {
+// recordOriginalUID stamps the given UID onto meta's annotations, unless the
+// object was never assigned one (e.g. it was constructed client-side).
+func recordOriginalUID(meta *metav1.ObjectMeta, annotationKey string, uid types.UID) {
+	if uid == "" {
+		return
+	}
+	if meta.Annotations == nil {
+		meta.Annotations = map[string]string{}
+	}
+	meta.Annotations[annotationKey] = string(uid)
+}
+
+func exportServiceListForReplay(ctx context.Context, latestSvc *servingv1.Service, client clientservingv1.KnServingClient, withRevisions bool, preserveUIDs bool) (runtime.Object, error) {
 	if !withRevisions {
-		return exportLatestService(latestSvc, false), nil
+		return exportLatestService(latestSvc, false, preserveUIDs), nil
 	}
-	var exportedSvcItems []servingv1.Service
 
-	revisionList, revsMap, err := getRevisionsToExport(ctx, latestSvc, client)
+	exportedSvcItems, err := exportServiceItemsForReplay(ctx, latestSvc, client, withRevisions, preserveUIDs)
 	if err != nil {
 		return nil, err
 	}
 
-	for _, revision := range revisionList.Items {
-		//construct service only for active revisions
-		if revsMap[revision.ObjectMeta.Name] && revision.ObjectMeta.Name != latestSvc.Spec.Template.ObjectMeta.Name {
-			exportedSvcItems = append(exportedSvcItems, constructServiceFromRevision(latestSvc, revision.DeepCopy()))
-		}
-	}
-
-	//add latest service, add traffic if more than one revision exist
-	exportedSvcItems = append(exportedSvcItems, *(exportLatestService(latestSvc, len(revisionList.Items) > 1)))
-
 	typeMeta := metav1.TypeMeta{
 		APIVersion: "v1",
 		Kind:       "List",
@@ -263,7 +433,34 @@ func exportServiceListForReplay(ctx context.Context, latestSvc *servingv1.Servic
 	return exportedSvcList, nil
 }
 
-func exportForKNImport(ctx context.Context, latestSvc *servingv1.Service, client clientservingv1.KnServingClient, withRevisions bool) (*clientv1alpha1.Export, error) {
+// exportServiceItemsForReplay produces the flattened list of Service items
+// (one per historical revision plus the latest) that back both the
+// single-service and bulk '--mode=replay' export paths.
+func exportServiceItemsForReplay(ctx context.Context, latestSvc *servingv1.Service, client clientservingv1.KnServingClient, withRevisions bool, preserveUIDs bool) ([]servingv1.Service, error) {
+	if !withRevisions {
+		return []servingv1.Service{*(exportLatestService(latestSvc, false, preserveUIDs))}, nil
+	}
+	var exportedSvcItems []servingv1.Service
+
+	revisionList, revsMap, err := getRevisionsToExport(ctx, latestSvc, client)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, revision := range revisionList.Items {
+		//construct service only for active revisions
+		if revsMap[revision.ObjectMeta.Name] && revision.ObjectMeta.Name != latestSvc.Spec.Template.ObjectMeta.Name {
+			exportedSvcItems = append(exportedSvcItems, constructServiceFromRevision(latestSvc, revision.DeepCopy(), preserveUIDs))
+		}
+	}
+
+	//add latest service, add traffic if more than one revision exist
+	exportedSvcItems = append(exportedSvcItems, *(exportLatestService(latestSvc, len(revisionList.Items) > 1, preserveUIDs)))
+
+	return exportedSvcItems, nil
+}
+
+func exportForKNImport(ctx context.Context, latestSvc *servingv1.Service, client clientservingv1.KnServingClient, withRevisions bool, preserveUIDs bool) (*clientv1alpha1.Export, error) {
 	var exportedRevItems []servingv1.Revision
 	revisionHistoryCount := 0
 	if withRevisions {
@@ -275,7 +472,7 @@ func exportForKNImport(ctx context.Context, latestSvc *servingv1.Service, client
 		for _, revision := range revisionList.Items {
 			//append only active revisions, no latest revision
 			if revsMap[revision.ObjectMeta.Name] && revision.ObjectMeta.Name != latestSvc.Spec.Template.ObjectMeta.Name {
-				exportedRevItems = append(exportedRevItems, exportRevision(revision.DeepCopy()))
+				exportedRevItems = append(exportedRevItems, exportRevision(revision.DeepCopy(), preserveUIDs))
 			}
 		}
 		revisionHistoryCount = len(revisionList.Items)
@@ -288,7 +485,7 @@ func exportForKNImport(ctx context.Context, latestSvc *servingv1.Service, client
 	knExport := &clientv1alpha1.Export{
 		TypeMeta: typeMeta,
 		Spec: clientv1alpha1.ExportSpec{
-			Service:   *(exportLatestService(latestSvc, revisionHistoryCount > 1)),
+			Service:   *(exportLatestService(latestSvc, revisionHistoryCount > 1, preserveUIDs)),
 			Revisions: exportedRevItems,
 		},
 	}
@@ -373,19 +570,32 @@ func stripIgnoredAnnotationsFromRevisionTemplate(template *servingv1.RevisionTem
 	}
 }
 
-func stripIgnoredLabelsFromService(svc *servingv1.Service) {
+// stripIgnoredLabelsFromService removes the ignored labels, unless
+// preserveUIDs is set: IgnoredServiceLabels includes the configuration/
+// service UID labels used for cross-cluster pod correlation, so
+// '--preserve-uids' keeps them intact.
+func stripIgnoredLabelsFromService(svc *servingv1.Service, preserveUIDs bool) {
+	if preserveUIDs {
+		return
+	}
 	for _, label := range IgnoredServiceLabels {
 		delete(svc.ObjectMeta.Labels, label)
 	}
 }
 
-func stripIgnoredLabelsFromRevision(rev *servingv1.Revision) {
+func stripIgnoredLabelsFromRevision(rev *servingv1.Revision, preserveUIDs bool) {
+	if preserveUIDs {
+		return
+	}
 	for _, label := range IgnoredRevisionLabels {
 		delete(rev.ObjectMeta.Labels, label)
 	}
 }
 
-func stripIgnoredLabelsFromRevisionTemplate(template *servingv1.RevisionTemplateSpec) {
+func stripIgnoredLabelsFromRevisionTemplate(template *servingv1.RevisionTemplateSpec, preserveUIDs bool) {
+	if preserveUIDs {
+		return
+	}
 	for _, label := range IgnoredRevisionLabels {
 		delete(template.ObjectMeta.Labels, label)
 	}
@@ -0,0 +1,137 @@
+// Copyright © 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"k8s.io/cli-runtime/pkg/printers"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"knative.dev/client/pkg/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// sourceContextAnnotation is stamped onto every Service exported via
+// '--contexts'/'--all-contexts' to record which kubeconfig context it came
+// from. Referenced ConfigMaps/Secrets and exported Revisions are not tagged.
+const sourceContextAnnotation = "client.knative.dev/source-context"
+
+// resolveContexts turns '--contexts'/'--all-contexts' into the concrete,
+// ordered list of kubeconfig context names to export from.
+func resolveContexts(p *commands.KnParams, contextsFlag string, allContexts bool) ([]string, error) {
+	if !allContexts {
+		contexts := strings.Split(contextsFlag, ",")
+		for i := range contexts {
+			contexts[i] = strings.TrimSpace(contexts[i])
+		}
+		return contexts, nil
+	}
+
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	if p.KubeCfgPath != "" {
+		rules.ExplicitPath = p.KubeCfgPath
+	}
+	kubeconfig, err := rules.Load()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig to resolve '--all-contexts': %w", err)
+	}
+	if len(kubeconfig.Contexts) == 0 {
+		return nil, errors.New("no contexts found in kubeconfig for '--all-contexts'")
+	}
+	contexts := make([]string, 0, len(kubeconfig.Contexts))
+	for name := range kubeconfig.Contexts {
+		contexts = append(contexts, name)
+	}
+	sort.Strings(contexts)
+	return contexts, nil
+}
+
+// newServingClientForContext builds a KnServingClient against a specific
+// kubeconfig context, falling back to the currently configured context when
+// none is given.
+func newServingClientForContext(p *commands.KnParams, context, namespace string) (clientservingv1.KnServingClient, error) {
+	if context == "" {
+		return p.NewServingClient(namespace)
+	}
+	contextParams := *p
+	contextParams.Context = context
+	return contextParams.NewServingClient(namespace)
+}
+
+// exportServiceAcrossContexts exports the named service from every given
+// kubeconfig context, tagging each exported Service with its source context and
+// wrapping the result in a single list.
+func exportServiceAcrossContexts(cmd *cobra.Command, p *commands.KnParams, serviceName string, contexts []string, printer printers.ResourcePrinter) error {
+	withRevisions, err := cmd.Flags().GetBool("with-revisions")
+	if err != nil {
+		return err
+	}
+	mode, err := cmd.Flags().GetString("mode")
+	if err != nil {
+		return err
+	}
+	preserveUIDs, err := cmd.Flags().GetBool("preserve-uids")
+	if err != nil {
+		return err
+	}
+	namespace, err := p.GetNamespace(cmd)
+	if err != nil {
+		return err
+	}
+
+	sources := make([]exportSource, 0, len(contexts))
+	for _, context := range contexts {
+		client, err := newServingClientForContext(p, context, namespace)
+		if err != nil {
+			return err
+		}
+		sources = append(sources, exportSource{namespace: namespace, client: client, context: context})
+	}
+
+	fetchNamedService := func(ctx context.Context, source exportSource) ([]*servingv1.Service, error) {
+		svc, err := source.client.GetService(ctx, serviceName)
+		if err != nil {
+			return nil, err
+		}
+		return []*servingv1.Service{svc}, nil
+	}
+
+	return exportServicesFromSources(cmd, p, sources, mode, withRevisions, preserveUIDs, printer, fetchNamedService)
+}
+
+// tagServicesWithSourceContext records the kubeconfig context each item was
+// exported from.
+func tagServicesWithSourceContext(items []servingv1.Service, context string) {
+	for i := range items {
+		tagServiceWithSourceContext(&items[i], context)
+	}
+}
+
+// tagServiceWithSourceContext records the kubeconfig context a single
+// service was exported from.
+func tagServiceWithSourceContext(svc *servingv1.Service, context string) {
+	if svc.ObjectMeta.Annotations == nil {
+		svc.ObjectMeta.Annotations = map[string]string{}
+	}
+	svc.ObjectMeta.Annotations[sourceContextAnnotation] = context
+}
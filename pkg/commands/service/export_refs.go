@@ -0,0 +1,224 @@
+// Copyright © 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+
+	"knative.dev/client/pkg/commands"
+	corev1client "knative.dev/client/pkg/core/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// redactedSecretAnnotationPrefix keys, per redacted data key, record the
+// SHA256 of the original Secret value that '--redact-secrets' replaced.
+const redactedSecretAnnotationPrefix = "client.knative.dev/redactedSHA256-"
+
+const redactedSecretValuePlaceholder = "**REDACTED**"
+
+// referencedObjectsForService reads the '--include-referenced' and
+// '--redact-secrets' flags and, if requested, fetches the ConfigMaps and
+// Secrets referenced by the service's revision template, against p's
+// configured kubeconfig context.
+func referencedObjectsForService(cmd *cobra.Command, p *commands.KnParams, service *servingv1.Service, namespace string) ([]corev1.ConfigMap, []corev1.Secret, error) {
+	return referencedObjectsForServiceInContext(cmd, p, service, namespace, "")
+}
+
+// referencedObjectsForServiceInContext is referencedObjectsForService's
+// context-aware variant, used by '--contexts'/'--all-contexts' so that each
+// context's ConfigMaps/Secrets are fetched from that context's cluster
+// rather than from p's single configured one. An empty context falls back
+// to p's configured context, mirroring newServingClientForContext.
+func referencedObjectsForServiceInContext(cmd *cobra.Command, p *commands.KnParams, service *servingv1.Service, namespace, context string) ([]corev1.ConfigMap, []corev1.Secret, error) {
+	includeReferenced, err := cmd.Flags().GetBool("include-referenced")
+	if err != nil {
+		return nil, nil, err
+	}
+	if !includeReferenced {
+		return nil, nil, nil
+	}
+	redactSecrets, err := cmd.Flags().GetBool("redact-secrets")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	kubeClient, err := kubeClientForContext(p, context)
+	if err != nil {
+		return nil, nil, err
+	}
+	coreClient := corev1client.NewKnCoreClient(kubeClient.CoreV1(), namespace)
+	return fetchReferencedObjects(cmd.Context(), service, coreClient, redactSecrets)
+}
+
+// kubeClientForContext builds a Kubernetes client set against a specific
+// kubeconfig context, falling back to p's configured context when none is
+// given, the core/v1 counterpart of newServingClientForContext.
+func kubeClientForContext(p *commands.KnParams, context string) (kubernetes.Interface, error) {
+	if context == "" {
+		return p.NewKubeClient()
+	}
+	contextParams := *p
+	contextParams.Context = context
+	return contextParams.NewKubeClient()
+}
+
+// fetchReferencedObjects fetches every ConfigMap and Secret referenced from
+// the service's containers (env, envFrom) and volumes.
+func fetchReferencedObjects(ctx context.Context, service *servingv1.Service, coreClient corev1client.KnCoreClient, redactSecrets bool) ([]corev1.ConfigMap, []corev1.Secret, error) {
+	configMapNames, secretNames := referencedConfigMapAndSecretNames(service)
+
+	configMaps := make([]corev1.ConfigMap, 0, len(configMapNames))
+	for _, name := range sortedStringSet(configMapNames) {
+		configMap, err := coreClient.GetConfigMap(ctx, name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get ConfigMap %q referenced by service %q: %w", name, service.ObjectMeta.Name, err)
+		}
+		configMap = configMap.DeepCopy()
+		stripManagedObjectMeta(&configMap.ObjectMeta)
+		configMaps = append(configMaps, *configMap)
+	}
+
+	secrets := make([]corev1.Secret, 0, len(secretNames))
+	for _, name := range sortedStringSet(secretNames) {
+		secret, err := coreClient.GetSecret(ctx, name)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get Secret %q referenced by service %q: %w", name, service.ObjectMeta.Name, err)
+		}
+		secret = secret.DeepCopy()
+		stripManagedObjectMeta(&secret.ObjectMeta)
+		if redactSecrets {
+			redactSecretData(secret)
+		}
+		secrets = append(secrets, *secret)
+	}
+
+	return configMaps, secrets, nil
+}
+
+// referencedConfigMapAndSecretNames walks the revision template's containers
+// and volumes for ConfigMap/Secret references.
+func referencedConfigMapAndSecretNames(service *servingv1.Service) (map[string]bool, map[string]bool) {
+	configMapNames := map[string]bool{}
+	secretNames := map[string]bool{}
+
+	for _, container := range service.Spec.Template.Spec.Containers {
+		for _, env := range container.Env {
+			if env.ValueFrom == nil {
+				continue
+			}
+			if ref := env.ValueFrom.ConfigMapKeyRef; ref != nil {
+				configMapNames[ref.Name] = true
+			}
+			if ref := env.ValueFrom.SecretKeyRef; ref != nil {
+				secretNames[ref.Name] = true
+			}
+		}
+		for _, envFrom := range container.EnvFrom {
+			if envFrom.ConfigMapRef != nil {
+				configMapNames[envFrom.ConfigMapRef.Name] = true
+			}
+			if envFrom.SecretRef != nil {
+				secretNames[envFrom.SecretRef.Name] = true
+			}
+		}
+	}
+
+	for _, volume := range service.Spec.Template.Spec.Volumes {
+		if volume.ConfigMap != nil {
+			configMapNames[volume.ConfigMap.Name] = true
+		}
+		if volume.Secret != nil {
+			secretNames[volume.Secret.SecretName] = true
+		}
+	}
+
+	return configMapNames, secretNames
+}
+
+func sortedStringSet(set map[string]bool) []string {
+	names := make([]string, 0, len(set))
+	for name := range set {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// stripManagedObjectMeta removes the cluster-managed metadata that would
+// otherwise make a re-applied ConfigMap/Secret diverge from its source, the
+// same way export.go strips it from the exported Service and Revisions.
+func stripManagedObjectMeta(meta *metav1.ObjectMeta) {
+	meta.ResourceVersion = ""
+	meta.UID = ""
+	meta.CreationTimestamp = metav1.Time{}
+	meta.ManagedFields = nil
+	delete(meta.Annotations, "kubectl.kubernetes.io/last-applied-configuration")
+}
+
+// redactSecretData replaces every value in a Secret's Data with a
+// placeholder, recording its original SHA256 in an annotation so the
+// redaction can be verified without storing the original secret.
+func redactSecretData(secret *corev1.Secret) {
+	if len(secret.Data) == 0 {
+		return
+	}
+	if secret.Annotations == nil {
+		secret.Annotations = map[string]string{}
+	}
+	for key, value := range secret.Data {
+		secret.Annotations[redactedSecretAnnotationPrefix+key] = fmt.Sprintf("%x", sha256.Sum256(value))
+		secret.Data[key] = []byte(redactedSecretValuePlaceholder)
+	}
+	secret.StringData = nil
+}
+
+// buildReplayList wraps the exported Service items for '--mode=replay' in a
+// plain 'v1 List', prepending any referenced ConfigMaps/Secrets so that a
+// single 'kubectl apply -f' recreates the whole runnable unit. When there is
+// nothing to prepend it keeps the existing 'ServiceList' shape unchanged.
+func buildReplayList(services []servingv1.Service, configMaps []corev1.ConfigMap, secrets []corev1.Secret) runtime.Object {
+	if len(configMaps) == 0 && len(secrets) == 0 {
+		return &servingv1.ServiceList{
+			TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "List"},
+			Items:    services,
+		}
+	}
+
+	items := make([]runtime.RawExtension, 0, len(configMaps)+len(secrets)+len(services))
+	for i := range configMaps {
+		items = append(items, runtime.RawExtension{Object: &configMaps[i]})
+	}
+	for i := range secrets {
+		items = append(items, runtime.RawExtension{Object: &secrets[i]})
+	}
+	for i := range services {
+		items = append(items, runtime.RawExtension{Object: &services[i]})
+	}
+
+	return &metav1.List{
+		TypeMeta: metav1.TypeMeta{APIVersion: "v1", Kind: "List"},
+		Items:    items,
+	}
+}
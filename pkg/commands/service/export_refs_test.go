@@ -0,0 +1,92 @@
+// Copyright © 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestReferencedConfigMapAndSecretNames(t *testing.T) {
+	svc := &servingv1.Service{}
+	svc.Spec.Template.Spec.Containers = []corev1.Container{{
+		Env: []corev1.EnvVar{
+			{Name: "FOO", ValueFrom: &corev1.EnvVarSource{ConfigMapKeyRef: &corev1.ConfigMapKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "cm-env"},
+			}}},
+			{Name: "BAR", ValueFrom: &corev1.EnvVarSource{SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: "secret-env"},
+			}}},
+			{Name: "BAZ", Value: "plain"},
+		},
+		EnvFrom: []corev1.EnvFromSource{
+			{ConfigMapRef: &corev1.ConfigMapEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "cm-envfrom"}}},
+			{SecretRef: &corev1.SecretEnvSource{LocalObjectReference: corev1.LocalObjectReference{Name: "secret-envfrom"}}},
+		},
+	}}
+	svc.Spec.Template.Spec.Volumes = []corev1.Volume{
+		{VolumeSource: corev1.VolumeSource{ConfigMap: &corev1.ConfigMapVolumeSource{
+			LocalObjectReference: corev1.LocalObjectReference{Name: "cm-vol"},
+		}}},
+		{VolumeSource: corev1.VolumeSource{Secret: &corev1.SecretVolumeSource{SecretName: "secret-vol"}}},
+	}
+
+	configMapNames, secretNames := referencedConfigMapAndSecretNames(svc)
+
+	wantConfigMaps := []string{"cm-env", "cm-envfrom", "cm-vol"}
+	wantSecrets := []string{"secret-env", "secret-envfrom", "secret-vol"}
+
+	if got := sortedStringSet(configMapNames); !reflect.DeepEqual(got, wantConfigMaps) {
+		t.Errorf("configMapNames = %v, want %v", got, wantConfigMaps)
+	}
+	if got := sortedStringSet(secretNames); !reflect.DeepEqual(got, wantSecrets) {
+		t.Errorf("secretNames = %v, want %v", got, wantSecrets)
+	}
+}
+
+func TestRedactSecretData(t *testing.T) {
+	secret := &corev1.Secret{
+		Data: map[string][]byte{
+			"password": []byte("hunter2"),
+		},
+	}
+
+	redactSecretData(secret)
+
+	if got := string(secret.Data["password"]); got != redactedSecretValuePlaceholder {
+		t.Errorf("Data[password] = %q, want placeholder %q", got, redactedSecretValuePlaceholder)
+	}
+	annotationKey := redactedSecretAnnotationPrefix + "password"
+	if _, ok := secret.Annotations[annotationKey]; !ok {
+		t.Errorf("expected annotation %q recording the original SHA256, got %v", annotationKey, secret.Annotations)
+	}
+}
+
+func TestSortedStringSet(t *testing.T) {
+	set := map[string]bool{"b": true, "a": true, "c": true}
+	got := sortedStringSet(set)
+	want := []string{"a", "b", "c"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("sortedStringSet() = %v, want %v", got, want)
+	}
+	if !sort.StringsAreSorted(got) {
+		t.Errorf("sortedStringSet() result not sorted: %v", got)
+	}
+}
@@ -0,0 +1,109 @@
+// Copyright © 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/cli-runtime/pkg/printers"
+
+	clientv1alpha1 "knative.dev/client/pkg/apis/client/v1alpha1"
+	"knative.dev/client/pkg/commands"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// exportSource is one (namespace, client) pair to export from, optionally
+// tagged with the kubeconfig context it came from. It's the shared unit both
+// the bulk '--selector'/'--all-namespaces' export and the '--contexts'/
+// '--all-contexts' fan-out iterate over, so a new export flag only needs to
+// be threaded through exportServicesFromSources once to reach both.
+type exportSource struct {
+	namespace string
+	client    clientservingv1.KnServingClient
+	context   string
+}
+
+// fetchServicesFunc resolves the services to export from a single source:
+// every service matching a selector for the bulk export, or the one named
+// service for the per-context fan-out.
+type fetchServicesFunc func(ctx context.Context, source exportSource) ([]*servingv1.Service, error)
+
+// exportServicesFromSources exports every service returned by fetch for each
+// source, as a single List (--mode=replay) or ExportList (default mode).
+func exportServicesFromSources(cmd *cobra.Command, p *commands.KnParams, sources []exportSource, mode string, withRevisions, preserveUIDs bool, printer printers.ResourcePrinter, fetch fetchServicesFunc) error {
+	ctx := cmd.Context()
+
+	if mode == ModeReplay {
+		var items []servingv1.Service
+		var configMaps []corev1.ConfigMap
+		var secrets []corev1.Secret
+		for _, source := range sources {
+			services, err := fetch(ctx, source)
+			if err != nil {
+				return err
+			}
+			for _, svc := range services {
+				svcItems, err := exportServiceItemsForReplay(ctx, svc, source.client, withRevisions, preserveUIDs)
+				if err != nil {
+					return err
+				}
+				if source.context != "" {
+					tagServicesWithSourceContext(svcItems, source.context)
+				}
+				items = append(items, svcItems...)
+				svcConfigMaps, svcSecrets, err := referencedObjectsForServiceInContext(cmd, p, svc, source.namespace, source.context)
+				if err != nil {
+					return err
+				}
+				configMaps = append(configMaps, svcConfigMaps...)
+				secrets = append(secrets, svcSecrets...)
+			}
+		}
+		return printer.PrintObj(buildReplayList(items, configMaps, secrets), cmd.OutOrStdout())
+	}
+
+	// default is export mode
+	var exports []clientv1alpha1.Export
+	for _, source := range sources {
+		services, err := fetch(ctx, source)
+		if err != nil {
+			return err
+		}
+		for _, svc := range services {
+			knExport, err := exportForKNImport(ctx, svc, source.client, withRevisions, preserveUIDs)
+			if err != nil {
+				return err
+			}
+			knExport.Spec.ConfigMaps, knExport.Spec.Secrets, err = referencedObjectsForServiceInContext(cmd, p, svc, source.namespace, source.context)
+			if err != nil {
+				return err
+			}
+			if source.context != "" {
+				tagServiceWithSourceContext(&knExport.Spec.Service, source.context)
+			}
+			exports = append(exports, *knExport)
+		}
+	}
+	exportList := &clientv1alpha1.ExportList{
+		TypeMeta: metav1.TypeMeta{APIVersion: "client.knative.dev/v1alpha1", Kind: "ExportList"},
+		Items:    exports,
+	}
+	return printer.PrintObj(exportList, cmd.OutOrStdout())
+}
@@ -0,0 +1,240 @@
+// Copyright © 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"knative.dev/client/pkg/commands"
+	corev1client "knative.dev/client/pkg/core/v1"
+	clientservingv1 "knative.dev/client/pkg/serving/v1"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+// NewServiceImportCommand returns a new command for importing a service
+// from a file produced by 'kn service export', the inverse operation.
+func NewServiceImportCommand(p *commands.KnParams) *cobra.Command {
+	command := &cobra.Command{
+		Use:   "import -f FILENAME",
+		Short: "Import a service from an export file (Beta)",
+		Example: `
+  # Recreate a service, including its revision history, from a previous export
+  kn service import -f foo.yaml -n bar
+
+  # Round-trip an export straight into another namespace
+  kn service export foo -n bar -o yaml | kn service import -f - -n baz
+
+  # Validate an import without changing the cluster
+  kn service import -f foo.yaml -n bar --dry-run=client`,
+		// NOTE: only '--dry-run=client' is supported today; 'server' is
+		// reserved until KnServingClient grows a server-side dry-run option.
+		RunE: func(cmd *cobra.Command, args []string) error {
+			filename, err := cmd.Flags().GetString("filename")
+			if err != nil {
+				return err
+			}
+			if filename == "" {
+				return errors.New("'kn service import' requires the export file name given by '--filename'")
+			}
+			dryRun, err := cmd.Flags().GetString("dry-run")
+			if err != nil {
+				return err
+			}
+			// TODO: support 'server', which needs a server-side dry-run option
+			// on KnServingClient's Create/UpdateService that doesn't exist yet.
+			if dryRun != "" && dryRun != "client" {
+				return fmt.Errorf("invalid --dry-run value %q, must be 'client'", dryRun)
+			}
+
+			namespace, err := p.GetNamespace(cmd)
+			if err != nil {
+				return err
+			}
+			client, err := p.NewServingClient(namespace)
+			if err != nil {
+				return err
+			}
+			kubeClient, err := p.NewKubeClient()
+			if err != nil {
+				return err
+			}
+			coreClient := corev1client.NewKnCoreClient(kubeClient.CoreV1(), namespace)
+
+			imported, err := readServiceExportFile(filename)
+			if err != nil {
+				return err
+			}
+			if imported.Service.ObjectMeta.Name == "" {
+				return fmt.Errorf("%q does not contain a named service to import", filename)
+			}
+
+			return importService(cmd.Context(), cmd.OutOrStdout(), client, coreClient, imported, dryRun)
+		},
+		SilenceUsage: true,
+	}
+	flags := command.Flags()
+	commands.AddNamespaceFlags(flags, false)
+	flags.StringP("filename", "f", "", "Export file to import, as produced by 'kn service export'. Use '-' to read from stdin.")
+	flags.String("dry-run", "", "Don't touch the cluster, only report what would be imported. Only 'client' (validate locally) is currently supported (Beta)")
+	return command
+}
+
+// importService recreates any ConfigMaps/Secrets referenced by the service
+// (as produced by 'kn service export --include-referenced'), then each
+// historical revision in generation order, then applies the final service
+// spec to converge traffic, mirroring the shape
+// 'kn service export --with-revisions' produced.
+func importService(ctx context.Context, out io.Writer, client clientservingv1.KnServingClient, coreClient corev1client.KnCoreClient, imported *exportedInput, dryRun string) error {
+	for i := range imported.ConfigMaps {
+		if err := applyImportedConfigMap(ctx, out, coreClient, &imported.ConfigMaps[i], dryRun); err != nil {
+			return fmt.Errorf("failed to recreate ConfigMap %q: %w", imported.ConfigMaps[i].ObjectMeta.Name, err)
+		}
+	}
+	for i := range imported.Secrets {
+		if err := applyImportedSecret(ctx, out, coreClient, &imported.Secrets[i], dryRun); err != nil {
+			return fmt.Errorf("failed to recreate Secret %q: %w", imported.Secrets[i].ObjectMeta.Name, err)
+		}
+	}
+
+	revisions := append([]servingv1.Revision(nil), imported.Revisions...)
+	sortImportRevisions(revisions)
+
+	for i := range revisions {
+		intermediate := constructServiceFromRevision(&imported.Service, &revisions[i], false)
+		if err := applyImportedService(ctx, out, client, &intermediate, dryRun); err != nil {
+			return fmt.Errorf("failed to recreate historical revision %q: %w", revisions[i].ObjectMeta.Name, err)
+		}
+	}
+
+	final := imported.Service
+	if err := applyImportedService(ctx, out, client, &final, dryRun); err != nil {
+		return fmt.Errorf("failed to converge service %q: %w", final.ObjectMeta.Name, err)
+	}
+	return nil
+}
+
+// sortImportRevisions orders revisions the same way 'export' does, so they
+// are recreated oldest generation first.
+func sortImportRevisions(revisions []servingv1.Revision) {
+	list := &servingv1.RevisionList{Items: revisions}
+	sortRevisions(list)
+}
+
+// importVerb reports whether an imported object will be created or updated,
+// based on whether it already exists in the cluster.
+func importVerb(exists bool) string {
+	if !exists {
+		return "create"
+	}
+	return "update"
+}
+
+// applyImportedService creates the service if it doesn't exist yet, or
+// updates it otherwise, so each successive revision converges on top of the
+// last. With '--dry-run' it reports the action without touching the cluster.
+func applyImportedService(ctx context.Context, out io.Writer, client clientservingv1.KnServingClient, svc *servingv1.Service, dryRun string) error {
+	existing, err := client.GetService(ctx, svc.ObjectMeta.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if dryRun != "" {
+		fmt.Fprintf(out, "Service '%s' would be %sd (dry run, revision '%s').\n", svc.ObjectMeta.Name, importVerb(existing != nil), svc.Spec.Template.ObjectMeta.Name)
+		return nil
+	}
+
+	if existing == nil {
+		if err := client.CreateService(ctx, svc); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Service '%s' created (revision '%s').\n", svc.ObjectMeta.Name, svc.Spec.Template.ObjectMeta.Name)
+		return nil
+	}
+
+	svc.ObjectMeta.ResourceVersion = existing.ObjectMeta.ResourceVersion
+	if err := client.UpdateService(ctx, svc); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Service '%s' updated (revision '%s').\n", svc.ObjectMeta.Name, svc.Spec.Template.ObjectMeta.Name)
+	return nil
+}
+
+// applyImportedConfigMap creates the ConfigMap if it doesn't exist yet, or
+// updates it otherwise. With '--dry-run' it reports the action without
+// touching the cluster.
+func applyImportedConfigMap(ctx context.Context, out io.Writer, coreClient corev1client.KnCoreClient, configMap *corev1.ConfigMap, dryRun string) error {
+	existing, err := coreClient.GetConfigMap(ctx, configMap.ObjectMeta.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if dryRun != "" {
+		fmt.Fprintf(out, "ConfigMap '%s' would be %sd (dry run).\n", configMap.ObjectMeta.Name, importVerb(existing != nil))
+		return nil
+	}
+
+	if existing == nil {
+		if err := coreClient.CreateConfigMap(ctx, configMap); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "ConfigMap '%s' created.\n", configMap.ObjectMeta.Name)
+		return nil
+	}
+
+	configMap.ObjectMeta.ResourceVersion = existing.ObjectMeta.ResourceVersion
+	if err := coreClient.UpdateConfigMap(ctx, configMap); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "ConfigMap '%s' updated.\n", configMap.ObjectMeta.Name)
+	return nil
+}
+
+// applyImportedSecret creates the Secret if it doesn't exist yet, or updates
+// it otherwise. With '--dry-run' it reports the action without touching the
+// cluster.
+func applyImportedSecret(ctx context.Context, out io.Writer, coreClient corev1client.KnCoreClient, secret *corev1.Secret, dryRun string) error {
+	existing, err := coreClient.GetSecret(ctx, secret.ObjectMeta.Name)
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	if dryRun != "" {
+		fmt.Fprintf(out, "Secret '%s' would be %sd (dry run).\n", secret.ObjectMeta.Name, importVerb(existing != nil))
+		return nil
+	}
+
+	if existing == nil {
+		if err := coreClient.CreateSecret(ctx, secret); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Secret '%s' created.\n", secret.ObjectMeta.Name)
+		return nil
+	}
+
+	secret.ObjectMeta.ResourceVersion = existing.ObjectMeta.ResourceVersion
+	if err := coreClient.UpdateSecret(ctx, secret); err != nil {
+		return err
+	}
+	fmt.Fprintf(out, "Secret '%s' updated.\n", secret.ObjectMeta.Name)
+	return nil
+}
@@ -0,0 +1,50 @@
+// Copyright © 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"knative.dev/serving/pkg/apis/serving"
+	servingv1 "knative.dev/serving/pkg/apis/serving/v1"
+)
+
+func TestSortImportRevisions(t *testing.T) {
+	revisions := []servingv1.Revision{
+		{ObjectMeta: metav1.ObjectMeta{Name: "foo-00003", Labels: map[string]string{serving.ConfigurationGenerationLabelKey: "3"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "foo-00001", Labels: map[string]string{serving.ConfigurationGenerationLabelKey: "1"}}},
+		{ObjectMeta: metav1.ObjectMeta{Name: "foo-00002", Labels: map[string]string{serving.ConfigurationGenerationLabelKey: "2"}}},
+	}
+
+	sortImportRevisions(revisions)
+
+	want := []string{"foo-00001", "foo-00002", "foo-00003"}
+	for i, name := range want {
+		if revisions[i].ObjectMeta.Name != name {
+			t.Errorf("revisions[%d].Name = %q, want %q", i, revisions[i].ObjectMeta.Name, name)
+		}
+	}
+}
+
+func TestImportVerb(t *testing.T) {
+	if got := importVerb(false); got != "create" {
+		t.Errorf("importVerb(false) = %q, want %q", got, "create")
+	}
+	if got := importVerb(true); got != "update" {
+		t.Errorf("importVerb(true) = %q, want %q", got, "update")
+	}
+}
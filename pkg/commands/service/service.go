@@ -0,0 +1,34 @@
+// Copyright © 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package service
+
+import (
+	"github.com/spf13/cobra"
+
+	"knative.dev/client/pkg/commands"
+)
+
+// NewServiceCommand returns a new command for managing Knative services.
+func NewServiceCommand(p *commands.KnParams) *cobra.Command {
+	serviceCmd := &cobra.Command{
+		Use:     "service",
+		Short:   "Manage Knative services",
+		Aliases: []string{"services", "svc"},
+	}
+	serviceCmd.AddCommand(NewServiceExportCommand(p))
+	serviceCmd.AddCommand(NewServiceDiffCommand(p))
+	serviceCmd.AddCommand(NewServiceImportCommand(p))
+	return serviceCmd
+}
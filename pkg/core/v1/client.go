@@ -0,0 +1,108 @@
+// Copyright © 2021 The Knative Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package v1 provides a small client for the plain Kubernetes objects (e.g.
+// ConfigMaps, Secrets) that 'kn' occasionally needs alongside its Knative
+// clients, without pulling the whole of client-go into every command.
+package v1
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	corev1client "k8s.io/client-go/kubernetes/typed/core/v1"
+)
+
+// KnCoreClient is the client used for plain Kubernetes core/v1 resources,
+// analogous to KnServingClient for Knative resources.
+type KnCoreClient interface {
+	// GetConfigMap gets a ConfigMap by name in the configured namespace.
+	GetConfigMap(ctx context.Context, name string) (*corev1.ConfigMap, error)
+
+	// GetSecret gets a Secret by name in the configured namespace.
+	GetSecret(ctx context.Context, name string) (*corev1.Secret, error)
+
+	// CreateConfigMap creates a ConfigMap in the configured namespace.
+	CreateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error
+
+	// UpdateConfigMap updates a ConfigMap in the configured namespace.
+	UpdateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error
+
+	// CreateSecret creates a Secret in the configured namespace.
+	CreateSecret(ctx context.Context, secret *corev1.Secret) error
+
+	// UpdateSecret updates a Secret in the configured namespace.
+	UpdateSecret(ctx context.Context, secret *corev1.Secret) error
+
+	// Namespace returns the namespace this client is configured for.
+	Namespace() string
+}
+
+type knCoreClient struct {
+	client    corev1client.CoreV1Interface
+	namespace string
+}
+
+// NewKnCoreClient creates a new client facade for the given namespace.
+func NewKnCoreClient(client corev1client.CoreV1Interface, namespace string) KnCoreClient {
+	return &knCoreClient{client: client, namespace: namespace}
+}
+
+// GetConfigMap gets a ConfigMap by name.
+func (c *knCoreClient) GetConfigMap(ctx context.Context, name string) (*corev1.ConfigMap, error) {
+	configMap, err := c.client.ConfigMaps(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return configMap, nil
+}
+
+// GetSecret gets a Secret by name.
+func (c *knCoreClient) GetSecret(ctx context.Context, name string) (*corev1.Secret, error) {
+	secret, err := c.client.Secrets(c.namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return secret, nil
+}
+
+// CreateConfigMap creates a ConfigMap.
+func (c *knCoreClient) CreateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error {
+	_, err := c.client.ConfigMaps(c.namespace).Create(ctx, configMap, metav1.CreateOptions{})
+	return err
+}
+
+// UpdateConfigMap updates a ConfigMap.
+func (c *knCoreClient) UpdateConfigMap(ctx context.Context, configMap *corev1.ConfigMap) error {
+	_, err := c.client.ConfigMaps(c.namespace).Update(ctx, configMap, metav1.UpdateOptions{})
+	return err
+}
+
+// CreateSecret creates a Secret.
+func (c *knCoreClient) CreateSecret(ctx context.Context, secret *corev1.Secret) error {
+	_, err := c.client.Secrets(c.namespace).Create(ctx, secret, metav1.CreateOptions{})
+	return err
+}
+
+// UpdateSecret updates a Secret.
+func (c *knCoreClient) UpdateSecret(ctx context.Context, secret *corev1.Secret) error {
+	_, err := c.client.Secrets(c.namespace).Update(ctx, secret, metav1.UpdateOptions{})
+	return err
+}
+
+// Namespace returns the namespace this client is configured for.
+func (c *knCoreClient) Namespace() string {
+	return c.namespace
+}